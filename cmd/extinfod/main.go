@@ -0,0 +1,36 @@
+// Command extinfod runs an HTTP+JSON gateway exposing cached extinfo for one or more Sauerbraten servers.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/sauerbraten/extinfo"
+	"github.com/sauerbraten/extinfo/httpapi"
+)
+
+func main() {
+	listenAddr := flag.String("listen", ":8080", "address to listen on")
+	masterAddr := flag.String("master", "", "master server address; enables the aggregated /servers endpoint if set")
+	requestTimeout := flag.Duration("request-timeout", 5*time.Second, "timeout for each query made to a game server")
+	rateLimit := flag.Float64("rate-limit", 5, "max outgoing UDP queries per second, per game server")
+	flag.Parse()
+
+	cfg := httpapi.Config{
+		RequestTimeout: *requestTimeout,
+		RateLimit:      rate.Limit(*rateLimit),
+	}
+
+	if *masterAddr != "" {
+		cfg.Master = extinfo.NewMaster(*masterAddr, *requestTimeout)
+	}
+
+	gateway := httpapi.NewGateway(cfg)
+
+	log.Printf("extinfod listening on %s", *listenAddr)
+	log.Fatal(http.ListenAndServe(*listenAddr, gateway.Handler()))
+}
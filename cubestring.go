@@ -0,0 +1,140 @@
+package extinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// colorEscape is the byte Sauerbraten uses within strings to introduce a color code, followed by a single palette digit.
+const colorEscape = '\f'
+
+// cubePalette maps a color-code digit to its RGB hex value, matching Cube 2's default color palette.
+var cubePalette = map[byte]string{
+	'0': "#707070",
+	'1': "#dc0000",
+	'2': "#00dc00",
+	'3': "#dcdc00",
+	'4': "#0000dc",
+	'5': "#dc00dc",
+	'6': "#00dcdc",
+	'7': "#f0f0f0",
+	'8': "#ff8000",
+	'9': "#b0b0b0",
+}
+
+// ansiPalette maps the same color-code digits to ANSI SGR foreground color codes.
+var ansiPalette = map[byte]string{
+	'0': "90",
+	'1': "31",
+	'2': "32",
+	'3': "33",
+	'4': "34",
+	'5': "35",
+	'6': "36",
+	'7': "97",
+	'8': "33",
+	'9': "37",
+}
+
+// Segment is one contiguously colored run of text within a CubeString.
+type Segment struct {
+	Text  string
+	Color string // RGB hex, e.g. "#dc0000"; empty if no color code precedes this run
+}
+
+// CubeString is a string as sent by a Sauerbraten server, which may contain color codes (a colorEscape byte followed by a single palette digit, e.g. "^f3" in the game's own notation). The raw form is retained so callers that need it are not forced to reconstruct it from the parsed representation.
+type CubeString string
+
+// Plain strips all color codes and returns the remaining text.
+func (c CubeString) Plain() string {
+	var b strings.Builder
+
+	s := string(c)
+	for i := 0; i < len(s); i++ {
+		if s[i] == colorEscape {
+			i++ // also skip the palette digit following the escape, if any
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// Segments splits the CubeString into contiguously colored runs, in order.
+func (c CubeString) Segments() []Segment {
+	segments := []Segment{}
+
+	var b strings.Builder
+	color := ""
+
+	flush := func() {
+		if b.Len() > 0 {
+			segments = append(segments, Segment{Text: b.String(), Color: color})
+			b.Reset()
+		}
+	}
+
+	s := string(c)
+	for i := 0; i < len(s); i++ {
+		if s[i] == colorEscape && i+1 < len(s) {
+			flush()
+			color = cubePalette[s[i+1]]
+			i++
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	flush()
+
+	return segments
+}
+
+// ANSI translates the color codes into ANSI SGR escape sequences, ready to be printed to a terminal.
+func (c CubeString) ANSI() string {
+	var b strings.Builder
+
+	s := string(c)
+	for i := 0; i < len(s); i++ {
+		if s[i] == colorEscape && i+1 < len(s) {
+			if code, ok := ansiPalette[s[i+1]]; ok {
+				fmt.Fprintf(&b, "\x1b[%sm", code)
+			}
+			i++
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	if strings.ContainsRune(s, colorEscape) {
+		b.WriteString("\x1b[0m")
+	}
+
+	return b.String()
+}
+
+// HTML translates the color codes into <span style="color:#..."> tags wrapping the colored text.
+func (c CubeString) HTML() string {
+	var b strings.Builder
+
+	for _, segment := range c.Segments() {
+		if segment.Color == "" {
+			b.WriteString(segment.Text)
+			continue
+		}
+		fmt.Fprintf(&b, `<span style="color:%s">%s</span>`, segment.Color, segment.Text)
+	}
+
+	return b.String()
+}
+
+// MarshalJSON emits the CubeString as {"raw":"...","plain":"..."}, so JSON consumers get both the original wire form and the human-readable text without implementing color-code parsing themselves.
+func (c CubeString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Raw   string `json:"raw"`
+		Plain string `json:"plain"`
+	}{
+		Raw:   string(c),
+		Plain: c.Plain(),
+	})
+}
@@ -2,280 +2,362 @@
 package extinfo
 
 import (
+	"context"
 	"errors"
 	"net"
-)
 
-// the current position in a response ([]byte)
-// needed, since values are encoded in variable amount of bytes
-// global to not have to pass around an int on every dump
-var positionInResponse int
+	"github.com/sauerbraten/cubecode"
+)
 
 // Constants describing the type of information to query for
 const (
 	EXTENDED_INFORMATION = 0
-	BASIC_INFORMATION = 1
+	BASIC_INFORMATION    = 1
 )
 
 // Constants describing the type of extended information to query for
 const (
-	UPTIME = 0
+	UPTIME      = 0
 	PLAYERSTATS = 1
-	TEAMSCORE = 2
+	TEAMSCORE   = 2
 )
 
-// GetTeamsScores queries a Sauerbraten server at addr on port for the teams' names and scores and returns the parsed response and/or an error in case something went wrong or the server is not running a team mode. Parsed response means that the int value sent as game mode is translated into the human readable name, e.g. '12' -> "insta ctf".
-func GetTeamsScores(addr string, port int) (TeamsScores, error) {
-	teamsScoresRaw, err := GetTeamsScoresRaw(addr, port)
-	teamsScores := TeamsScores{getGameModeName(teamsScoresRaw.GameMode), teamsScoresRaw.SecsLeft, teamsScoresRaw.Scores}
-	return teamsScores, err
-}
+// Constants describing parts of the server's response to an extended info request
+const (
+	EXTENDED_INFO_ACK     = -1  // server's acknowledgement that it understood the request
+	EXTENDED_INFO_VERSION = 105 // extinfo protocol version this package speaks
+)
 
-// GetTeamsScoresRaw queries a Sauerbraten server at addr on port for the teams' names and scores and returns the raw response and/or an error in case something went wrong or the server is not running a team mode.
-func GetTeamsScoresRaw(addr string, port int) (TeamsScoresRaw, error) {
-	teamsScoresRaw := TeamsScoresRaw{}
+// playerInfoPacketSize is the fixed size, in bytes, of each player's chunk within a GetAllPlayerInfo response.
+const playerInfoPacketSize = 64
 
-	response, err := queryServer(addr, port, buildRequest(EXTENDED_INFORMATION, 2, 0))
+// GetBasicInfo queries the server and returns the parsed response or an error in case something went wrong. Parsed response means that the int values sent as game mode and master mode are translated into the human readable name, e.g. '12' -> "insta ctf".
+func (s *Server) GetBasicInfo(ctx context.Context) (basicInfo BasicInfo, err error) {
+	response, err := s.queryServerCtx(ctx, buildRequest(BASIC_INFORMATION, 0, 0))
 	if err != nil {
-		return teamsScoresRaw, err
+		return
 	}
 
-	positionInResponse = 0
-
-	// first int is EXTENDED_INFORMATION = 0
-	_ = dumpInt(response)
-
-	// next int is TEAMSCORE = 2
-	_ = dumpInt(response)
-
-	// next int is EXT_ACK = -1
-	_ = dumpInt(response)
-
-	// next int is EXT_VERSION
-	_ = dumpInt(response)
+	// first int is BASIC_INFORMATION
+	if _, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	// next int describes wether the server runs a team mode or not
-	isTeamMode := true
-	if dumpInt(response) != 0 {
-		isTeamMode = false
+	if basicInfo.NumberOfClients, err = response.ReadInt(); err != nil {
+		return
 	}
 
-	teamsScoresRaw.GameMode = dumpInt(response)
-	teamsScoresRaw.SecsLeft = dumpInt(response)
+	// next int is always 5, the number of additional attributes after the playercount and the strings for map and description
+	if _, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	if !isTeamMode {
-		// no team scores following
-		return teamsScoresRaw, errors.New("server is not running a team mode")
+	if basicInfo.ProtocolVersion, err = response.ReadInt(); err != nil {
+		return
 	}
 
-	name := ""
-	score := 0
+	gameMode, err := response.ReadInt()
+	if err != nil {
+		return
+	}
+	basicInfo.GameMode = getGameModeName(gameMode)
 
-	for positionInResponse < len(response) {
-		name = dumpString(response)
-		score = dumpInt(response)
+	if basicInfo.SecsLeft, err = response.ReadInt(); err != nil {
+		return
+	}
+	if basicInfo.MaxNumberOfClients, err = response.ReadInt(); err != nil {
+		return
+	}
 
-		bases := make([]int, 0)
+	masterMode, err := response.ReadInt()
+	if err != nil {
+		return
+	}
+	basicInfo.MasterMode = getMasterModeName(masterMode)
 
-		for i := 0; i < dumpInt(response); i++ {
-			bases = append(bases, dumpInt(response))
-		}
+	basicInfo.Map, err = response.ReadString()
+	if err != nil {
+		return
+	}
 
-		teamsScoresRaw.Scores = append(teamsScoresRaw.Scores, TeamScore{name, score, bases})
+	description, err := response.ReadString()
+	if err != nil {
+		return
 	}
+	basicInfo.Description = CubeString(description)
 
-	return teamsScoresRaw, nil
+	return basicInfo, nil
 }
 
-
-// GetBasicInfo queries a Sauerbraten server at addr on port and returns the parsed response or an error in case something went wrong. Parsed response means that the int values sent as game mode and master mode are translated into the human readable name, e.g. '12' -> "insta ctf".
-func GetBasicInfo(addr string, port int) (BasicInfo, error) {
-	basicInfo := BasicInfo{}
-
-	response, err := queryServer(addr, port, buildRequest(BASIC_INFORMATION, 0, 0))
+// GetBasicInfoRaw queries the server and returns the raw response or an error in case something went wrong. Raw response means that the int values sent as game mode and master mode are NOT translated into the human readable name.
+func (s *Server) GetBasicInfoRaw(ctx context.Context) (basicInfoRaw BasicInfoRaw, err error) {
+	response, err := s.queryServerCtx(ctx, buildRequest(BASIC_INFORMATION, 0, 0))
 	if err != nil {
-		return basicInfo, err
+		return
 	}
 
-	positionInResponse = 0
+	// first int is BASIC_INFORMATION
+	if _, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	// first int is BASIC_INFORMATION = 1
-	_ = dumpInt(response)
+	if basicInfoRaw.NumberOfClients, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	basicInfo.NumberOfClients = dumpInt(response)
 	// next int is always 5, the number of additional attributes after the playercount and the strings for map and description
-	//numberOfAttributes := dumpInt(response)
-	_ = dumpInt(response)
-	basicInfo.ProtocolVersion = dumpInt(response)
-	basicInfo.GameMode = getGameModeName(dumpInt(response))
-	basicInfo.SecsLeft = dumpInt(response)
-	basicInfo.MaxNumberOfClients = dumpInt(response)
-	basicInfo.MasterMode = getMasterModeName(dumpInt(response))
-	basicInfo.Map = dumpString(response)
-	basicInfo.Description = dumpString(response)
+	if _, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	return basicInfo, nil
-}
+	if basicInfoRaw.ProtocolVersion, err = response.ReadInt(); err != nil {
+		return
+	}
+	if basicInfoRaw.GameMode, err = response.ReadInt(); err != nil {
+		return
+	}
+	if basicInfoRaw.SecsLeft, err = response.ReadInt(); err != nil {
+		return
+	}
+	if basicInfoRaw.MaxNumberOfClients, err = response.ReadInt(); err != nil {
+		return
+	}
+	if basicInfoRaw.MasterMode, err = response.ReadInt(); err != nil {
+		return
+	}
+	if basicInfoRaw.Map, err = response.ReadString(); err != nil {
+		return
+	}
+	if basicInfoRaw.Description, err = response.ReadString(); err != nil {
+		return
+	}
 
-// GetBasicInfoRaw queries a Sauerbraten server at addr on port and returns the raw response or an error in case something went wrong. Raw response means that the int values sent as game mode and master mode are NOT translated into the human readable name.
-func GetBasicInfoRaw(addr string, port int) (BasicInfoRaw, error) {
-	basicInfoRaw := BasicInfoRaw{}
+	return basicInfoRaw, nil
+}
 
-	response, err := queryServer(addr, port, buildRequest(BASIC_INFORMATION, 0, 0))
+// GetPlayerInfo returns the parsed information about the player with the given clientNum.
+func (s *Server) GetPlayerInfo(ctx context.Context, clientNum int) (playerInfo PlayerInfo, err error) {
+	response, err := s.queryServerCtx(ctx, buildRequest(EXTENDED_INFORMATION, PLAYERSTATS, clientNum))
 	if err != nil {
-		return basicInfoRaw, err
+		return
 	}
 
-	positionInResponse = 0
+	// throw away EXTENDED_INFORMATION, PLAYERSTATS, clientNum, server ACK and server VERSION
+	for i := 0; i < 5; i++ {
+		if _, err = response.ReadInt(); err != nil {
+			return
+		}
+	}
 
-	// first int is BASIC_INFORMATION = 1
-	_ = dumpInt(response)
-	basicInfoRaw.NumberOfClients = dumpInt(response)
-	// next int is always 5, the number of additional attributes after the playercount and the strings for map and description
-	//numberOfAttributes := dumpInt(response)
-	_ = dumpInt(response)
-	basicInfoRaw.ProtocolVersion = dumpInt(response)
-	basicInfoRaw.GameMode = dumpInt(response)
-	basicInfoRaw.SecsLeft = dumpInt(response)
-	basicInfoRaw.MaxNumberOfClients = dumpInt(response)
-	basicInfoRaw.MasterMode = dumpInt(response)
-	basicInfoRaw.Map = dumpString(response)
-	basicInfoRaw.Description = dumpString(response)
+	errorFlag, err := response.ReadInt()
+	if err != nil {
+		return
+	}
+	if errorFlag != 0x00 {
+		err = errors.New("extinfo: invalid cn")
+		return
+	}
 
-	return basicInfoRaw, nil
+	// throw away server PLAYERSTATS_RESP_STATS
+	if _, err = response.ReadInt(); err != nil {
+		return
+	}
+
+	return parsePlayerInfo(response)
 }
 
-// GetUptime returns the uptime of the server in seconds.
-func GetUptime(addr string, port int) (int, error) {
-	response, err := queryServer(addr, port, buildRequest(EXTENDED_INFORMATION, UPTIME, 0))
+// GetPlayerInfoRaw returns the raw information about the player with the given clientNum.
+func (s *Server) GetPlayerInfoRaw(ctx context.Context, clientNum int) (playerInfoRaw PlayerInfoRaw, err error) {
+	response, err := s.queryServerCtx(ctx, buildRequest(EXTENDED_INFORMATION, PLAYERSTATS, clientNum))
 	if err != nil {
-		return -1, err
+		return
 	}
 
-	positionInResponse = 0
-
-	// first int is EXTENDED_INFORMATION
-	_ = dumpInt(response)
+	// throw away EXTENDED_INFORMATION, PLAYERSTATS, clientNum, server ACK and server VERSION
+	for i := 0; i < 5; i++ {
+		if _, err = response.ReadInt(); err != nil {
+			return
+		}
+	}
 
-	// next int is EXT_UPTIME = 0
-	_ = dumpInt(response)
+	errorFlag, err := response.ReadInt()
+	if err != nil {
+		return
+	}
+	if errorFlag != 0x00 {
+		err = errors.New("extinfo: invalid cn")
+		return
+	}
 
-	// next int is EXT_ACK = -1
-	_ = dumpInt(response)
+	// throw away server PLAYERSTATS_RESP_STATS
+	if _, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	// next int is EXT_VERSION
-	_ = dumpInt(response)
+	if playerInfoRaw.ClientNum, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Ping, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Name, err = response.ReadString(); err != nil {
+		return
+	}
+	if playerInfoRaw.Team, err = response.ReadString(); err != nil {
+		return
+	}
+	if playerInfoRaw.Frags, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Flags, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Deaths, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Teamkills, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Damage, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Health, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Armour, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Weapon, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.Privilege, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfoRaw.State, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	// next int is the actual uptime
-	uptime := dumpInt(response)
+	ip, err := readIPv4(response)
+	if err != nil {
+		return
+	}
+	playerInfoRaw.IP = ip
 
-	return uptime, nil
+	return playerInfoRaw, nil
 }
 
-// GetPlayerInfo returns the parsed information about the player with the given clientNum.
-func GetPlayerInfo(addr string, port int, clientNum int) (PlayerInfo, error) {
-	playerInfo := PlayerInfo{}
+// parsePlayerInfo parses a single player's chunk of a GetPlayerInfo or GetAllPlayerInfo response. It is its own function because it is used by both.
+func parsePlayerInfo(response *cubecode.Packet) (playerInfo PlayerInfo, err error) {
+	if playerInfo.ClientNum, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfo.Ping, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	response, err := queryServer(addr, port, buildRequest(EXTENDED_INFORMATION, PLAYERSTATS, clientNum))
+	name, err := response.ReadString()
 	if err != nil {
-		return playerInfo, err
+		return
 	}
+	playerInfo.Name = CubeString(name)
 
-	if response[5] != 0x00 {
-		// there was an error
-		return playerInfo, errors.New("invalid cn")
+	team, err := response.ReadString()
+	if err != nil {
+		return
 	}
+	playerInfo.Team = CubeString(team)
 
-	// throw away 7 first ints (EXTENDED_INFORMATION, PLAYERSTATS, clientNum, server ACK byte, server VERSION byte, server NO_ERROR byte, server PLAYERSTATS_RESP_STATS byte)
-	response = response[7:]
+	if playerInfo.Frags, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfo.Flags, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfo.Deaths, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfo.Teamkills, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfo.Damage, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfo.Health, err = response.ReadInt(); err != nil {
+		return
+	}
+	if playerInfo.Armour, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	playerInfo = parsePlayerInfo(response)
+	weapon, err := response.ReadInt()
+	if err != nil {
+		return
+	}
+	playerInfo.Weapon = getWeaponName(weapon)
 
-	return playerInfo, nil
-}
+	privilege, err := response.ReadInt()
+	if err != nil {
+		return
+	}
+	playerInfo.Privilege = getPrivilegeName(privilege)
 
-// GetPlayerInfoRaw returns the raw information about the player with the given clientNum.
-func GetPlayerInfoRaw(addr string, port int, clientNum int) (PlayerInfoRaw, error) {
-	playerInfoRaw := PlayerInfoRaw{}
+	state, err := response.ReadInt()
+	if err != nil {
+		return
+	}
+	playerInfo.State = getStateName(state)
 
-	response, err := queryServer(addr, port, buildRequest(EXTENDED_INFORMATION, PLAYERSTATS, clientNum))
+	ip, err := readIPv4(response)
 	if err != nil {
-		return playerInfoRaw, err
-	}
-
-	if response[5] != 0x00 {
-		// there was an error
-		return playerInfoRaw, errors.New("invalid cn")
-	}
-
-	// throw away 7 first ints (EXTENDED_INFORMATION, PLAYERSTATS, clientNum, server ACK byte, server VERSION byte, server NO_ERROR byte, server PLAYERSTATS_RESP_STATS byte)
-	response = response[7:]
-	
-	positionInResponse = 0
-
-	playerInfoRaw.ClientNum = dumpInt(response)
-	playerInfoRaw.Ping = dumpInt(response)
-	playerInfoRaw.Name = dumpString(response)
-	playerInfoRaw.Team = dumpString(response)
-	playerInfoRaw.Frags = dumpInt(response)
-	playerInfoRaw.Flags = dumpInt(response)
-	playerInfoRaw.Deaths = dumpInt(response)
-	playerInfoRaw.Teamkills = dumpInt(response)
-	playerInfoRaw.Damage = dumpInt(response)
-	playerInfoRaw.Health = dumpInt(response)
-	playerInfoRaw.Armour = dumpInt(response)
-	playerInfoRaw.Weapon = dumpInt(response)
-	playerInfoRaw.Privilege = dumpInt(response)
-	playerInfoRaw.State = dumpInt(response)
-	// IP from next 4 bytes
-	ip := response[positionInResponse:positionInResponse+4]
-	playerInfoRaw.IP = net.IPv4(ip[0], ip[1], ip[2], ip[3])
+		return
+	}
+	playerInfo.IP = ip
 
-	return playerInfoRaw, nil
+	return playerInfo, nil
 }
 
-// own function, because it is used in GetPlayerInfo() + GetAllPlayerInfo()
-func parsePlayerInfo(response []byte) PlayerInfo {
-	playerInfo := PlayerInfo{}
-
-	positionInResponse = 0
-
-	playerInfo.ClientNum = dumpInt(response)
-	playerInfo.Ping = dumpInt(response)
-	playerInfo.Name = dumpString(response)
-	playerInfo.Team = dumpString(response)
-	playerInfo.Frags = dumpInt(response)
-	playerInfo.Flags = dumpInt(response)
-	playerInfo.Deaths = dumpInt(response)
-	playerInfo.Teamkills = dumpInt(response)
-	playerInfo.Damage = dumpInt(response)
-	playerInfo.Health = dumpInt(response)
-	playerInfo.Armour = dumpInt(response)
-	playerInfo.Weapon = getWeaponName(dumpInt(response))
-	playerInfo.Privilege = getPrivilegeName(dumpInt(response))
-	playerInfo.State = getStateName(dumpInt(response))
-	// IP from next 4 bytes
-	ipBytes := response[positionInResponse:positionInResponse+4]
-	playerInfo.IP = net.IPv4(ipBytes[0], ipBytes[1], ipBytes[2], ipBytes[3])
-
-	return playerInfo
+// readIPv4 reads the 4 bytes cubecode.Packet encodes a player's IP address as.
+func readIPv4(response *cubecode.Packet) (net.IP, error) {
+	b := make([]byte, 4)
+	for i := range b {
+		octet, err := response.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		b[i] = octet
+	}
+	return net.IPv4(b[0], b[1], b[2], b[3]), nil
 }
 
-// GetAllPlayerInfo returns the Information of all Players (including spectators) as a []PlayerInfo
-func GetAllPlayerInfo(addr string, port int) ([]PlayerInfo, error) {
-	allPlayerInfo := []PlayerInfo{}
-
-	response, err := queryServer(addr, port, buildRequest(EXTENDED_INFORMATION, PLAYERSTATS, -1))
+// GetAllPlayerInfo returns the information of all players (including spectators) as a []PlayerInfo.
+func (s *Server) GetAllPlayerInfo(ctx context.Context) (allPlayerInfo []PlayerInfo, err error) {
+	response, err := s.queryServerCtx(ctx, buildRequest(EXTENDED_INFORMATION, PLAYERSTATS, -1))
 	if err != nil {
-		return allPlayerInfo, err
+		return
 	}
 
-	// response is multiple 64-byte responses, one for each player
-	playerCount := len(response) / 64
+	// response is a sequence of fixed-size per-player packets
+	for response.Len() > 0 {
+		var playerResponse *cubecode.Packet
+		playerResponse, err = response.SubPacket(playerInfoPacketSize)
+		if err != nil {
+			return
+		}
+
+		// throw away EXTENDED_INFORMATION, PLAYERSTATS, clientNum, server ACK, server VERSION, server NO_ERROR and server PLAYERSTATS_RESP_STATS
+		for i := 0; i < 7; i++ {
+			if _, err = playerResponse.ReadInt(); err != nil {
+				return
+			}
+		}
+
+		var playerInfo PlayerInfo
+		playerInfo, err = parsePlayerInfo(playerResponse)
+		if err != nil {
+			return
+		}
 
-	// parse each 64 byte packet (without the first 7 bytes) on its own and append to allPlayerInfo
-	for i := 0; i < playerCount; i++ {
-		allPlayerInfo = append(allPlayerInfo, parsePlayerInfo(response[i*64+7:(i*64)+64]))
+		allPlayerInfo = append(allPlayerInfo, playerInfo)
 	}
 
 	return allPlayerInfo, nil
@@ -0,0 +1,105 @@
+package extinfo
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sauerbraten/cubecode"
+)
+
+// buildInts encodes values the same way a Sauerbraten server would, using the package's own variable-length int scheme.
+func buildInts(values ...int) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		writeRequestInt(&buf, v)
+	}
+	return buf.Bytes()
+}
+
+// buildPlayerInfoPacket builds the byte layout parsePlayerInfo expects, using single-byte ints and null-terminated strings (every int used in these tests fits in one byte).
+func buildPlayerInfoPacket(clientNum, ping int, name, team string, frags, flags, deaths, teamkills, damage, health, armour, weapon, privilege, state int, ip []byte) []byte {
+	b := []byte{
+		byte(clientNum), byte(ping),
+	}
+	b = append(b, []byte(name)...)
+	b = append(b, 0x00)
+	b = append(b, []byte(team)...)
+	b = append(b, 0x00)
+	b = append(b, byte(frags), byte(flags), byte(deaths), byte(teamkills), byte(damage), byte(health), byte(armour), byte(weapon), byte(privilege), byte(state))
+	b = append(b, ip...)
+	return b
+}
+
+func TestParsePlayerInfo(t *testing.T) {
+	valid := buildPlayerInfoPacket(3, 50, "gibby", "good", 10, 0, 2, 0, 100, 100, 0, 0, 0, 0, []byte{127, 0, 0, 1})
+
+	tests := []struct {
+		name    string
+		packet  []byte
+		wantErr bool
+	}{
+		{
+			name:   "valid packet",
+			packet: valid,
+		},
+		{
+			name:    "truncated before IP",
+			packet:  valid[:len(valid)-4],
+			wantErr: true,
+		},
+		{
+			name:    "empty packet",
+			packet:  []byte{},
+			wantErr: true,
+		},
+		{
+			// 0x81 announces a 4-byte int, but only one byte follows, so the
+			// second of readInt's four ReadByte calls hits the empty buffer.
+			name:    "truncated multi-byte varint",
+			packet:  []byte{0x81, 0x01},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePlayerInfo(cubecode.NewPacket(tt.packet))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePlayerInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGetPlayerInfoInvalidCN drives GetPlayerInfo's own header parsing (rather than parsePlayerInfo, which only runs after the error-flag check has already passed) against a server that reports a non-zero error flag, i.e. the clientNum doesn't exist.
+func TestGetPlayerInfoInvalidCN(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	const clientNum = 3
+
+	go func() {
+		buf := make([]byte, 1024)
+		_, remote, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		// EXTENDED_INFORMATION, PLAYERSTATS, clientNum, server ACK, server VERSION, then a non-zero error flag
+		response := buildInts(EXTENDED_INFORMATION, PLAYERSTATS, clientNum, EXTENDED_INFO_ACK, EXTENDED_INFO_VERSION, 0x01)
+		conn.WriteToUDP(response, remote)
+	}()
+
+	s := &Server{addr: conn.LocalAddr().(*net.UDPAddr), timeOut: time.Second}
+
+	_, err = s.GetPlayerInfo(context.Background(), clientNum)
+	if err == nil {
+		t.Fatal("GetPlayerInfo() with non-zero error flag: expected error, got nil")
+	}
+}
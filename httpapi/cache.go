@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCache is a minimal in-process cache whose entries expire after a fixed duration. It exists so bursty HTTP clients don't trigger a fresh UDP query to the game server on every request.
+type ttlCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+func (c *ttlCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+func (c *ttlCache) set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cacheEntry{
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
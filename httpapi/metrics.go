@@ -0,0 +1,34 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// metrics holds the counters exposed on /metrics in Prometheus text exposition format.
+type metrics struct {
+	queries   uint64
+	timeouts  uint64
+	retries   uint64
+	cacheHits uint64
+}
+
+func (m *metrics) incQueries()   { atomic.AddUint64(&m.queries, 1) }
+func (m *metrics) incTimeouts()  { atomic.AddUint64(&m.timeouts, 1) }
+func (m *metrics) incRetries()   { atomic.AddUint64(&m.retries, 1) }
+func (m *metrics) incCacheHits() { atomic.AddUint64(&m.cacheHits, 1) }
+
+// ServeHTTP renders the counters in the Prometheus text exposition format.
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	counter := func(name, help string, value uint64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, value)
+	}
+
+	counter("extinfod_queries_total", "Number of extinfo queries sent to game servers.", atomic.LoadUint64(&m.queries))
+	counter("extinfod_timeouts_total", "Number of extinfo queries that timed out.", atomic.LoadUint64(&m.timeouts))
+	counter("extinfod_retries_total", "Number of times an extinfo query was retried.", atomic.LoadUint64(&m.retries))
+	counter("extinfod_cache_hits_total", "Number of HTTP requests served from cache.", atomic.LoadUint64(&m.cacheHits))
+}
@@ -0,0 +1,223 @@
+// Package httpapi exposes cached, rate-limited access to Sauerbraten servers' extinfo over HTTP+JSON, as used by cmd/extinfod.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/sauerbraten/extinfo"
+)
+
+// CacheTTLs configures how long each endpoint's response may be served from cache before a fresh query is made.
+type CacheTTLs struct {
+	BasicInfo   time.Duration // defaults to 30s
+	PlayerInfo  time.Duration // defaults to 2s
+	TeamsScores time.Duration // defaults to 2s
+}
+
+// Config configures a Gateway.
+type Config struct {
+	CacheTTLs      CacheTTLs
+	RequestTimeout time.Duration   // timeout applied to every query made to a game server; defaults to 5s
+	RateLimit      rate.Limit      // max outgoing UDP queries per second, per game server address; defaults to 5
+	RateBurst      int             // burst size for RateLimit; defaults to 5
+	Master         *extinfo.Master // optional; enables the aggregated /servers endpoint
+}
+
+// Gateway is an HTTP+JSON front-end for the extinfo package. It caches responses per endpoint and rate-limits outgoing queries per game server address, so that bursty web clients don't hammer the game servers behind it.
+type Gateway struct {
+	cfg     Config
+	metrics metrics
+
+	basicInfoCache   *ttlCache
+	playerInfoCache  *ttlCache
+	teamsScoresCache *ttlCache
+
+	limitersMu sync.Mutex
+	limiters   map[string]*rate.Limiter
+}
+
+// NewGateway returns a Gateway ready to be mounted via Handler.
+func NewGateway(cfg Config) *Gateway {
+	if cfg.CacheTTLs.BasicInfo <= 0 {
+		cfg.CacheTTLs.BasicInfo = 30 * time.Second
+	}
+	if cfg.CacheTTLs.PlayerInfo <= 0 {
+		cfg.CacheTTLs.PlayerInfo = 2 * time.Second
+	}
+	if cfg.CacheTTLs.TeamsScores <= 0 {
+		cfg.CacheTTLs.TeamsScores = 2 * time.Second
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 5 * time.Second
+	}
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = 5
+	}
+	if cfg.RateBurst <= 0 {
+		cfg.RateBurst = 5
+	}
+
+	return &Gateway{
+		cfg:              cfg,
+		basicInfoCache:   newTTLCache(cfg.CacheTTLs.BasicInfo),
+		playerInfoCache:  newTTLCache(cfg.CacheTTLs.PlayerInfo),
+		teamsScoresCache: newTTLCache(cfg.CacheTTLs.TeamsScores),
+		limiters:         make(map[string]*rate.Limiter),
+	}
+}
+
+// Handler returns the http.Handler serving /servers/{addr}/basic, /players, /players/{cn}, /teams and /uptime, plus /servers (when cfg.Master is set) and /metrics.
+func (g *Gateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/servers/", g.handleServer)
+	if g.cfg.Master != nil {
+		mux.HandleFunc("/servers", g.handleServerList)
+	}
+	mux.Handle("/metrics", &g.metrics)
+
+	return mux
+}
+
+// handleServer dispatches /servers/{addr}/{endpoint} requests to the matching getter. addr is expected as "host:port", using the server's game port.
+func (g *Gateway) handleServer(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/servers/")
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 {
+		http.Error(w, "expected /servers/{addr}/{endpoint}", http.StatusBadRequest)
+		return
+	}
+
+	addr, endpoint := parts[0], parts[1]
+
+	server, err := g.serverFor(addr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !g.limiterFor(addr).Allow() {
+		http.Error(w, "rate limit exceeded for this server", http.StatusTooManyRequests)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), g.cfg.RequestTimeout)
+	defer cancel()
+
+	switch {
+	case endpoint == "basic":
+		g.serveCached(w, g.basicInfoCache, addr+"/basic", func() (interface{}, error) {
+			g.metrics.incQueries()
+			return server.GetBasicInfo(ctx)
+		})
+	case endpoint == "players":
+		g.serveCached(w, g.playerInfoCache, addr+"/players", func() (interface{}, error) {
+			g.metrics.incQueries()
+			return server.GetAllPlayerInfo(ctx)
+		})
+	case strings.HasPrefix(endpoint, "players/"):
+		cn, err := strconv.Atoi(strings.TrimPrefix(endpoint, "players/"))
+		if err != nil {
+			http.Error(w, "invalid client number", http.StatusBadRequest)
+			return
+		}
+		g.serveCached(w, g.playerInfoCache, addr+"/players/"+strconv.Itoa(cn), func() (interface{}, error) {
+			g.metrics.incQueries()
+			return server.GetPlayerInfo(ctx, cn)
+		})
+	case endpoint == "teams":
+		g.serveCached(w, g.teamsScoresCache, addr+"/teams", func() (interface{}, error) {
+			g.metrics.incQueries()
+			return server.GetTeamsScores(ctx)
+		})
+	case endpoint == "uptime":
+		g.serveCached(w, g.basicInfoCache, addr+"/uptime", func() (interface{}, error) {
+			g.metrics.incQueries()
+			return server.GetUptime(ctx)
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleServerList serves /servers, the aggregated listing of all public servers known to cfg.Master.
+func (g *Gateway) handleServerList(w http.ResponseWriter, r *http.Request) {
+	servers, err := g.cfg.Master.GetServerList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeJSON(w, servers)
+}
+
+// serveCached looks key up in cache, calling query and storing its result on a miss, then writes the (possibly cached) value as JSON.
+func (g *Gateway) serveCached(w http.ResponseWriter, cache *ttlCache, key string, query func() (interface{}, error)) {
+	if value, ok := cache.get(key); ok {
+		g.metrics.incCacheHits()
+		writeJSON(w, value)
+		return
+	}
+
+	value, err := query()
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			g.metrics.incTimeouts()
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	cache.set(key, value)
+	writeJSON(w, value)
+}
+
+// serverFor parses addr ("host:port") and returns an *extinfo.Server for it.
+func (g *Gateway) serverFor(addr string) (*extinfo.Server, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := extinfo.NewServer(host, port, g.cfg.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	server.OnRetry = g.metrics.incRetries
+
+	return server, nil
+}
+
+// limiterFor returns the rate limiter for addr, creating one on first use.
+func (g *Gateway) limiterFor(addr string) *rate.Limiter {
+	g.limitersMu.Lock()
+	defer g.limitersMu.Unlock()
+
+	limiter, ok := g.limiters[addr]
+	if !ok {
+		limiter = rate.NewLimiter(g.cfg.RateLimit, g.cfg.RateBurst)
+		g.limiters[addr] = limiter
+	}
+
+	return limiter
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
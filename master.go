@@ -0,0 +1,170 @@
+package extinfo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMasterAddr is the address of the official Sauerbraten master server.
+const DefaultMasterAddr = "master.sauerbraten.org:28787"
+
+// Master describes a Sauerbraten master server, which keeps track of the public game servers.
+type Master struct {
+	addr    string
+	timeOut time.Duration
+}
+
+// NewMaster returns a new Master client for the master server at addr. If addr is the empty string, DefaultMasterAddr is used.
+func NewMaster(addr string, timeOut time.Duration) *Master {
+	if addr == "" {
+		addr = DefaultMasterAddr
+	}
+
+	return &Master{addr, timeOut}
+}
+
+// GetServerList connects to the master server, requests the list of public servers and returns them as a slice of *Server, each ready to be queried.
+func (m *Master) GetServerList() ([]*Server, error) {
+	conn, err := net.DialTimeout("tcp", m.addr, m.timeOut)
+	if err != nil {
+		return nil, fmt.Errorf("extinfo: could not connect to master server: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(m.timeOut))
+
+	_, err = fmt.Fprintln(conn, "list")
+	if err != nil {
+		return nil, fmt.Errorf("extinfo: could not send list command to master server: %v", err)
+	}
+
+	servers := []*Server{}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[0] != "addserver" {
+			continue
+		}
+
+		port, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+
+		server, err := NewServer(fields[1], port, m.timeOut)
+		if err != nil {
+			continue
+		}
+
+		servers = append(servers, server)
+	}
+	if err := scanner.Err(); err != nil {
+		return servers, fmt.Errorf("extinfo: error reading server list from master server: %v", err)
+	}
+
+	return servers, nil
+}
+
+// QueryAllOptions configures the concurrency and timeout behaviour of QueryAll.
+type QueryAllOptions struct {
+	Workers        int           // number of servers queried concurrently; defaults to 10 if <= 0
+	PerCallTimeout time.Duration // timeout applied to every individual getter call; defaults to 5s if <= 0
+}
+
+// QueryAllResult bundles everything QueryAll could determine about a single server. Err is set if querying the basic info or the player info failed; TeamsScores is left at its zero value if the server is not running a team mode.
+type QueryAllResult struct {
+	Server      *Server
+	BasicInfo   BasicInfo
+	PlayerInfo  []PlayerInfo
+	TeamsScores TeamsScores
+	Err         error
+}
+
+// QueryAll queries every server in servers for its basic info, player info and teams scores, using a pool of opts.Workers goroutines, and returns the results keyed by the server's address (as returned by (*Server).Addr().String()). Querying stops early for servers not yet started on once ctx is done.
+func QueryAll(ctx context.Context, servers []*Server, opts QueryAllOptions) map[string]QueryAllResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 10
+	}
+
+	jobs := make(chan *Server)
+	resultsCh := make(chan QueryAllResult)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for server := range jobs {
+				resultsCh <- queryOne(ctx, server, opts.PerCallTimeout)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, server := range servers {
+			select {
+			case jobs <- server:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	results := make(map[string]QueryAllResult, len(servers))
+	for result := range resultsCh {
+		results[result.Server.Addr().String()] = result
+	}
+
+	return results
+}
+
+// queryOne runs the basic info, player info and teams scores getters for a single server, stopping as soon as one of the required calls fails. Each call is bounded by timeout, so a single server hanging on read cannot stall the whole pool.
+func queryOne(ctx context.Context, server *Server, timeout time.Duration) QueryAllResult {
+	result := QueryAllResult{Server: server}
+
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	basicInfo, err := server.GetBasicInfo(callCtx)
+	cancel()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.BasicInfo = basicInfo
+
+	callCtx, cancel = context.WithTimeout(ctx, timeout)
+	playerInfo, err := server.GetAllPlayerInfo(callCtx)
+	cancel()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.PlayerInfo = playerInfo
+
+	// teams scores are only available in team modes, so a failure here is not fatal to the result
+	callCtx, cancel = context.WithTimeout(ctx, timeout)
+	teamsScores, err := server.GetTeamsScores(callCtx)
+	cancel()
+	if err == nil {
+		result.TeamsScores = teamsScores
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+package extinfo
+
+// gameModeNames maps the int value Sauerbraten sends for the current game mode to its human-readable name.
+var gameModeNames = map[int]string{
+	0:  "demo",
+	1:  "ffa",
+	2:  "coop edit",
+	3:  "teamplay",
+	4:  "insta ctf",
+	5:  "insta",
+	6:  "effic ctf",
+	7:  "effic",
+	8:  "tactics",
+	9:  "tactics team",
+	10: "capture",
+	11: "regen capture",
+	12: "insta ctf",
+	13: "protect",
+	14: "hold",
+	15: "effic ctf",
+	16: "effic protect",
+	17: "effic hold",
+	18: "collect",
+	19: "insta collect",
+	20: "effic collect",
+}
+
+func getGameModeName(gameMode int) string {
+	if name, ok := gameModeNames[gameMode]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// masterModeNames maps the int value Sauerbraten sends for the current master mode to its human-readable name.
+var masterModeNames = map[int]string{
+	-1: "auth",
+	0:  "open",
+	1:  "veto",
+	2:  "locked",
+	3:  "private",
+	4:  "password",
+}
+
+func getMasterModeName(masterMode int) string {
+	if name, ok := masterModeNames[masterMode]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// weaponNames maps the int value Sauerbraten sends for a player's current weapon to its human-readable name.
+var weaponNames = map[int]string{
+	0: "fist",
+	1: "shotgun",
+	2: "chaingun",
+	3: "rocket launcher",
+	4: "rifle",
+	5: "grenade launcher",
+	6: "pistol",
+	7: "saw",
+}
+
+func getWeaponName(weapon int) string {
+	if name, ok := weaponNames[weapon]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// privilegeNames maps the int value Sauerbraten sends for a player's privilege level to its human-readable name.
+var privilegeNames = map[int]string{
+	0: "none",
+	1: "master",
+	2: "auth",
+	3: "admin",
+}
+
+func getPrivilegeName(privilege int) string {
+	if name, ok := privilegeNames[privilege]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// stateNames maps the int value Sauerbraten sends for a player's current state to its human-readable name.
+var stateNames = map[int]string{
+	0: "alive",
+	1: "dead",
+	2: "spawning",
+	3: "lagged",
+	4: "editing",
+	5: "spectator",
+}
+
+func getStateName(state int) string {
+	if name, ok := stateNames[state]; ok {
+		return name
+	}
+	return "unknown"
+}
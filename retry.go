@@ -0,0 +1,82 @@
+package extinfo
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/sauerbraten/cubecode"
+)
+
+// RetryPolicy describes how a Server retries a query after the transport itself fails, i.e. the request couldn't be sent or no response arrived within the server's timeout. Since queries are sent over UDP, packet loss is expected and a single lost packet should not surface as an error to the caller. It does not cover a response that arrives but fails to parse; see queryServerCtx.
+type RetryPolicy struct {
+	MaxAttempts int           // maximum number of attempts, including the first one
+	BaseDelay   time.Duration // delay before the first retry
+	MaxDelay    time.Duration // upper bound for the delay between retries
+	Factor      float64       // multiplier applied to the delay after every attempt
+	Jitter      float64       // fraction of the delay randomly added or subtracted, e.g. 0.2 for ±20%
+}
+
+// DefaultRetryPolicy is used by Server when no RetryPolicy has been set explicitly. It mirrors the defaults gRPC uses for its connection backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Factor:      1.6,
+	Jitter:      0.2,
+}
+
+// delay returns the backoff delay waited after attempt n (0-based) failed, i.e. before retry n+1, perturbed by the configured jitter.
+func (p RetryPolicy) delay(n int) time.Duration {
+	d := float64(p.BaseDelay)
+	for i := 0; i < n; i++ {
+		d *= p.Factor
+	}
+	if max := float64(p.MaxDelay); d > max {
+		d = max
+	}
+
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	return time.Duration(d)
+}
+
+// queryServerCtx sends request to the server and retries according to s.RetryPolicy whenever the attempt fails at the transport level (timeout, or the request/response couldn't be sent/read at all), honoring ctx between attempts. Public getters use this instead of calling queryServer directly so that transient UDP packet loss does not surface as an error. A response that arrives but fails to parse is NOT retried here: it is returned to the getter, which reports the parse error to its caller; scoping retries to the transport keeps semantically meaningful errors (e.g. "invalid cn") from being silently delayed by a futile retry loop.
+func (s *Server) queryServerCtx(ctx context.Context, request []byte) (response *cubecode.Packet, err error) {
+	policy := s.RetryPolicy
+	if policy.MaxAttempts <= 0 {
+		policy = DefaultRetryPolicy
+	}
+
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		response, err = s.queryServer(request)
+		if err == nil {
+			return response, nil
+		}
+
+		if attempt == policy.MaxAttempts-1 {
+			break
+		}
+
+		if s.OnRetry != nil {
+			s.OnRetry()
+		}
+
+		select {
+		case <-time.After(policy.delay(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,63 @@
+package extinfo
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/sauerbraten/cubecode"
+)
+
+// Server describes a Sauerbraten server and the connection details required to query it.
+type Server struct {
+	addr    *net.UDPAddr
+	timeOut time.Duration
+
+	// RetryPolicy controls how queryServerCtx retries a query after a transport-level failure (timeout, or the request/response couldn't be sent/read). The zero value means DefaultRetryPolicy is used.
+	RetryPolicy RetryPolicy
+
+	// OnRetry, if set, is called every time a query is retried. It exists so callers (e.g. the httpapi gateway) can keep their own metrics without this package depending on them.
+	OnRetry func()
+}
+
+// NewServer returns a Server for the game server at addr on port, ready to be queried. port should be the server's game port (not the info port! i.e. the port you use to connect in-game); the info port is derived from it as port+1, per the Sauerbraten protocol.
+func NewServer(addr string, port int, timeOut time.Duration) (*Server, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", addr, port+1))
+	if err != nil {
+		return nil, fmt.Errorf("extinfo: could not resolve server address: %v", err)
+	}
+
+	return &Server{addr: udpAddr, timeOut: timeOut}, nil
+}
+
+// Addr returns the server's resolved info-port address.
+func (s *Server) Addr() *net.UDPAddr {
+	return s.addr
+}
+
+// queryServer sends request to the server over UDP and returns the response as a *cubecode.Packet, or an error if the server did not respond within s.timeOut. Public getters call this through queryServerCtx rather than directly, so that a single lost packet is retried instead of surfacing as an error.
+func (s *Server) queryServer(request []byte) (*cubecode.Packet, error) {
+	conn, err := net.DialUDP("udp", nil, s.addr)
+	if err != nil {
+		return nil, fmt.Errorf("extinfo: could not connect to server: %v", err)
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(s.timeOut))
+	if err != nil {
+		return nil, fmt.Errorf("extinfo: could not set deadline: %v", err)
+	}
+
+	_, err = conn.Write(request)
+	if err != nil {
+		return nil, fmt.Errorf("extinfo: could not send request: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("extinfo: could not read response: %v", err)
+	}
+
+	return cubecode.NewPacket(buf[:n]), nil
+}
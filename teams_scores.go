@@ -1,14 +1,15 @@
 package extinfo
 
 import (
+	"context"
 	"errors"
 )
 
 // TeamScore (team score) contains the name of the team and the score, i.e. flags scored in flag modes / points gained for holding bases in capture modes / frags achieved in DM modes / skulls collected
 type TeamScore struct {
-	Name  string // name of the team, e.g. "good"
-	Score int    // amount of points (flags in ctf modes, frags in deathmatch modes, points in capture, skulls in collect)
-	Bases []int  // the numbers/IDs of the bases the team possesses (only used in capture modes)
+	Name  CubeString // name of the team, e.g. "good"; may contain color codes
+	Score int        // amount of points (flags in ctf modes, frags in deathmatch modes, points in capture, skulls in collect)
+	Bases []int      // the numbers/IDs of the bases the team possesses (only used in capture modes)
 }
 
 // TeamsScoresRaw (teams's scores) contains the game mode as raw int, the seconds left in the game, and a slice of TeamScores
@@ -24,64 +25,89 @@ type TeamsScores struct {
 	GameMode string // current game mode
 }
 
-// GetTeamsScoresRaw queries a Sauerbraten server at addr on port for the teams' names and scores and returns the raw response and/or an error in case something went wrong or the server is not running a team mode.
-func (s *Server) GetTeamsScoresRaw() (teamsScoresRaw TeamsScoresRaw, err error) {
-	teamsScoresRaw = TeamsScoresRaw{}
-
-	request := buildRequest(EXTENDED_INFO, EXTENDED_INFO_TEAMS_SCORES, 0)
-	response, err := s.queryServer(request)
+// GetTeamsScoresRaw queries a Sauerbraten server at addr on port for the teams' names and scores and returns the raw response and/or an error in case something went wrong or the server is not running a team mode. The request is retried according to s.RetryPolicy on timeout, honoring ctx between attempts.
+func (s *Server) GetTeamsScoresRaw(ctx context.Context) (teamsScoresRaw TeamsScoresRaw, err error) {
+	request := buildRequest(EXTENDED_INFORMATION, TEAMSCORE, 0)
+	response, err := s.queryServerCtx(ctx, request)
 	if err != nil {
 		return
 	}
 
-	// ignore first 3 bytes: EXTENDED_INFO, EXTENDED_INFO_TEAMS_SCORES, EXTENDED_INFO_ACK
-	response = response[3:]
-
-	positionInResponse = 0
+	// ignore EXTENDED_INFORMATION, TEAMSCORE and EXTENDED_INFO_ACK
+	for i := 0; i < 3; i++ {
+		if _, err = response.ReadInt(); err != nil {
+			return
+		}
+	}
 
 	// check for correct extinfo protocol version
-	if dumpInt(response) != EXTENDED_INFO_VERSION {
+	version, err := response.ReadInt()
+	if err != nil {
+		return
+	}
+	if version != EXTENDED_INFO_VERSION {
 		err = errors.New("extinfo: wrong extinfo protocol version")
 		return
 	}
 
 	// next int describes wether the server runs a team mode or not
-	isTeamMode := true
-	if dumpInt(response) != 0 {
-		isTeamMode = false
+	notTeamMode, err := response.ReadInt()
+	if err != nil {
+		return
 	}
 
-	teamsScoresRaw.GameMode = dumpInt(response)
-	teamsScoresRaw.SecsLeft = dumpInt(response)
+	if teamsScoresRaw.GameMode, err = response.ReadInt(); err != nil {
+		return
+	}
+	if teamsScoresRaw.SecsLeft, err = response.ReadInt(); err != nil {
+		return
+	}
 
-	if !isTeamMode {
+	if notTeamMode != 0 {
 		// no team scores following
 		err = errors.New("extinfo: server is not running a team mode")
 		return
 	}
 
-	for response[positionInResponse] != 0x0 {
-		name := dumpString(response)
-		score := dumpInt(response)
-		numBases := dumpInt(response)
+	for response.HasRemaining() {
+		var name string
+		name, err = response.ReadString()
+		if err != nil {
+			return
+		}
+		if name == "" {
+			// trailing terminator after the last team, not an actual team
+			break
+		}
 
-		bases := make([]int, 0)
+		var score, numBases int
+		if score, err = response.ReadInt(); err != nil {
+			return
+		}
+		if numBases, err = response.ReadInt(); err != nil {
+			return
+		}
 
+		bases := make([]int, 0, numBases)
 		for i := 0; i < numBases; i++ {
-			bases = append(bases, dumpInt(response))
+			var base int
+			if base, err = response.ReadInt(); err != nil {
+				return
+			}
+			bases = append(bases, base)
 		}
 
-		teamsScoresRaw.Scores = append(teamsScoresRaw.Scores, TeamScore{name, score, bases})
+		teamsScoresRaw.Scores = append(teamsScoresRaw.Scores, TeamScore{CubeString(name), score, bases})
 	}
 
 	return
 }
 
 // GetTeamsScores queries a Sauerbraten server at addr on port for the teams' names and scores and returns the parsed response and/or an error in case something went wrong or the server is not running a team mode. Parsed response means that the int value sent as game mode is translated into the human readable name, e.g. '12' -> "insta ctf".
-func (s *Server) GetTeamsScores() (TeamsScores, error) {
+func (s *Server) GetTeamsScores(ctx context.Context) (TeamsScores, error) {
 	teamsScores := TeamsScores{}
 
-	teamsScoresRaw, err := s.GetTeamsScoresRaw()
+	teamsScoresRaw, err := s.GetTeamsScoresRaw(ctx)
 	if err != nil {
 		return teamsScores, err
 	}
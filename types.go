@@ -0,0 +1,65 @@
+package extinfo
+
+import "net"
+
+// BasicInfo contains the most commonly requested information about a server and its game state, with the int values sent for game mode and master mode translated into their human-readable names.
+type BasicInfo struct {
+	NumberOfClients    int
+	ProtocolVersion    int
+	GameMode           string
+	SecsLeft           int
+	MaxNumberOfClients int
+	MasterMode         string
+	Map                string
+	Description        CubeString
+}
+
+// BasicInfoRaw is like BasicInfo, but keeps the game mode and master mode as the raw int sent by the server.
+type BasicInfoRaw struct {
+	NumberOfClients    int
+	ProtocolVersion    int
+	GameMode           int
+	SecsLeft           int
+	MaxNumberOfClients int
+	MasterMode         int
+	Map                string
+	Description        string
+}
+
+// PlayerInfo contains the information about a single player, with the int values sent for weapon, privilege and state translated into their human-readable names.
+type PlayerInfo struct {
+	ClientNum int
+	Ping      int
+	Name      CubeString
+	Team      CubeString
+	Frags     int
+	Flags     int
+	Deaths    int
+	Teamkills int
+	Damage    int
+	Health    int
+	Armour    int
+	Weapon    string
+	Privilege string
+	State     string
+	IP        net.IP
+}
+
+// PlayerInfoRaw is like PlayerInfo, but keeps weapon, privilege and state as the raw int sent by the server.
+type PlayerInfoRaw struct {
+	ClientNum int
+	Ping      int
+	Name      string
+	Team      string
+	Frags     int
+	Flags     int
+	Deaths    int
+	Teamkills int
+	Damage    int
+	Health    int
+	Armour    int
+	Weapon    int
+	Privilege int
+	State     int
+	IP        net.IP
+}
@@ -1,11 +1,15 @@
 package extinfo
 
-import "github.com/sauerbraten/cubecode"
+import (
+	"context"
 
-// GetUptime returns the uptime of the server in seconds.
-func (s *Server) GetUptime() (uptime int, err error) {
+	"github.com/sauerbraten/cubecode"
+)
+
+// GetUptime returns the uptime of the server in seconds. The request is retried according to s.RetryPolicy on timeout, honoring ctx between attempts.
+func (s *Server) GetUptime(ctx context.Context) (uptime int, err error) {
 	var response *cubecode.Packet
-	response, err = s.queryServer(buildRequest(EXTENDED_INFO, EXTENDED_INFO_UPTIME, 0))
+	response, err = s.queryServerCtx(ctx, buildRequest(EXTENDED_INFORMATION, UPTIME, 0))
 	if err != nil {
 		return
 	}
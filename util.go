@@ -0,0 +1,30 @@
+package extinfo
+
+import "bytes"
+
+// buildRequest builds the byte sequence to send to a server to query for the given information type, with up to two additional parameters (e.g. the extended info type and a client number). Unused trailing parameters should be passed as 0.
+func buildRequest(a, b, c int) []byte {
+	var buf bytes.Buffer
+	writeRequestInt(&buf, a)
+	writeRequestInt(&buf, b)
+	writeRequestInt(&buf, c)
+	return buf.Bytes()
+}
+
+// writeRequestInt encodes value the same way cubecode.Packet.WriteInt does, so that servers parsing our requests with the same variable-length int scheme see exactly what they expect.
+func writeRequestInt(buf *bytes.Buffer, value int) {
+	switch {
+	case value > -127 && value < 128:
+		buf.WriteByte(byte(value))
+	case value >= -0x8000 && value < 0x8000:
+		buf.WriteByte(0x80)
+		buf.WriteByte(byte(value))
+		buf.WriteByte(byte(value >> 8))
+	default:
+		buf.WriteByte(0x81)
+		buf.WriteByte(byte(value))
+		buf.WriteByte(byte(value >> 8))
+		buf.WriteByte(byte(value >> 16))
+		buf.WriteByte(byte(value >> 24))
+	}
+}
@@ -0,0 +1,202 @@
+package extinfo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of change a Watcher observed between two polls.
+type EventType int
+
+// The event types a Watcher can emit.
+const (
+	PlayerJoined EventType = iota
+	PlayerLeft
+	PlayerRenamed
+	MapChanged
+	GameModeChanged
+	MasterModeChanged
+	IntermissionStarted
+	TeamScoreChanged
+	PlayerFragged
+)
+
+// Event describes a single state change a Watcher observed since its previous poll. Only the fields relevant to Type are set.
+type Event struct {
+	Type     EventType
+	Server   *Server
+	Player   *PlayerInfo // set for PlayerJoined, PlayerLeft, PlayerRenamed, PlayerFragged
+	OldName  CubeString  // set for PlayerRenamed
+	Old      string      // set for MapChanged, GameModeChanged, MasterModeChanged
+	New      string      // set for MapChanged, GameModeChanged, MasterModeChanged
+	Team     CubeString  // set for TeamScoreChanged
+	OldScore int         // set for TeamScoreChanged, PlayerFragged
+	NewScore int         // set for TeamScoreChanged, PlayerFragged
+}
+
+// Snapshot is the last known state of a server, as observed by a Watcher. It is the basis for computing diffs between polls.
+type Snapshot struct {
+	BasicInfo   BasicInfo
+	Players     map[int]PlayerInfo // keyed by clientNum
+	TeamsScores TeamsScores
+}
+
+// Watcher polls a Server on a fixed interval and emits Events describing what changed since the previous poll. Use NewWatcher to create one and Close to stop it.
+type Watcher struct {
+	server   *Server
+	interval time.Duration
+
+	mu       sync.Mutex
+	snapshot Snapshot
+	polled   bool
+
+	events chan Event
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewWatcher starts polling server every interval and returns a Watcher streaming the observed changes on the channel returned by Subscribe. Call Close to stop polling.
+func NewWatcher(server *Server, interval time.Duration) *Watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := &Watcher{
+		server:   server,
+		interval: interval,
+		events:   make(chan Event),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	go w.run(ctx)
+
+	return w
+}
+
+// Subscribe returns the channel Events are sent on. The channel is closed once the Watcher is closed.
+func (w *Watcher) Subscribe() <-chan Event {
+	return w.events
+}
+
+// LastSnapshot returns the most recently polled state of the server.
+func (w *Watcher) LastSnapshot() Snapshot {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.snapshot
+}
+
+// Close stops polling and closes the event channel. It blocks until the polling goroutine has exited.
+func (w *Watcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *Watcher) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.poll(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// poll fetches the current state of w.server and diffs it against the previous snapshot, emitting an Event for every change found. Errors are swallowed: a failed poll simply leaves the previous snapshot in place until the next tick succeeds.
+func (w *Watcher) poll(ctx context.Context) {
+	basicInfo, err := w.server.GetBasicInfo(ctx)
+	if err != nil {
+		return
+	}
+
+	players, err := w.server.GetAllPlayerInfo(ctx)
+	if err != nil {
+		return
+	}
+
+	playersByClientNum := make(map[int]PlayerInfo, len(players))
+	for _, p := range players {
+		playersByClientNum[p.ClientNum] = p
+	}
+
+	// teams scores are only available in team modes, so ignore the error and keep the zero value
+	teamsScores, _ := w.server.GetTeamsScores(ctx)
+
+	next := Snapshot{BasicInfo: basicInfo, Players: playersByClientNum, TeamsScores: teamsScores}
+
+	w.mu.Lock()
+	prev := w.snapshot
+	hadPrev := w.polled
+	w.snapshot = next
+	w.polled = true
+	w.mu.Unlock()
+
+	if hadPrev {
+		w.diff(ctx, prev, next)
+	}
+}
+
+// diff compares prev and next and emits one Event per change it finds.
+func (w *Watcher) diff(ctx context.Context, prev, next Snapshot) {
+	if prev.BasicInfo.Map != next.BasicInfo.Map {
+		w.emit(ctx, Event{Type: MapChanged, Server: w.server, Old: prev.BasicInfo.Map, New: next.BasicInfo.Map})
+	}
+	if prev.BasicInfo.GameMode != next.BasicInfo.GameMode {
+		w.emit(ctx, Event{Type: GameModeChanged, Server: w.server, Old: prev.BasicInfo.GameMode, New: next.BasicInfo.GameMode})
+	}
+	if prev.BasicInfo.MasterMode != next.BasicInfo.MasterMode {
+		w.emit(ctx, Event{Type: MasterModeChanged, Server: w.server, Old: prev.BasicInfo.MasterMode, New: next.BasicInfo.MasterMode})
+	}
+	if prev.BasicInfo.SecsLeft > 0 && next.BasicInfo.SecsLeft == 0 {
+		w.emit(ctx, Event{Type: IntermissionStarted, Server: w.server})
+	}
+
+	for cn, player := range next.Players {
+		player := player
+
+		old, existed := prev.Players[cn]
+		if !existed {
+			w.emit(ctx, Event{Type: PlayerJoined, Server: w.server, Player: &player})
+			continue
+		}
+		if old.Name != player.Name {
+			w.emit(ctx, Event{Type: PlayerRenamed, Server: w.server, Player: &player, OldName: old.Name})
+		}
+		if player.Frags != old.Frags {
+			w.emit(ctx, Event{Type: PlayerFragged, Server: w.server, Player: &player, OldScore: old.Frags, NewScore: player.Frags})
+		}
+	}
+
+	for cn, player := range prev.Players {
+		if _, stillThere := next.Players[cn]; !stillThere {
+			player := player
+			w.emit(ctx, Event{Type: PlayerLeft, Server: w.server, Player: &player})
+		}
+	}
+
+	prevScores := make(map[CubeString]int, len(prev.TeamsScores.Scores))
+	for _, teamScore := range prev.TeamsScores.Scores {
+		prevScores[teamScore.Name] = teamScore.Score
+	}
+	for _, teamScore := range next.TeamsScores.Scores {
+		if oldScore, ok := prevScores[teamScore.Name]; ok && oldScore != teamScore.Score {
+			w.emit(ctx, Event{Type: TeamScoreChanged, Server: w.server, Team: teamScore.Name, OldScore: oldScore, NewScore: teamScore.Score})
+		}
+	}
+}
+
+// emit sends e on w.events, unless ctx is done first (e.g. Close was called while no one is receiving from Subscribe), in which case the event is dropped so Close can't deadlock.
+func (w *Watcher) emit(ctx context.Context, e Event) {
+	select {
+	case w.events <- e:
+	case <-ctx.Done():
+	}
+}